@@ -48,6 +48,7 @@ func (cli *DockerCli) CmdBuild(args ...string) error {
 	rm := cmd.Bool([]string{"-rm"}, true, "Remove intermediate containers after a successful build")
 	forceRm := cmd.Bool([]string{"-force-rm"}, false, "Always remove intermediate containers")
 	pull := cmd.Bool([]string{"-pull"}, false, "Always attempt to pull a newer version of the image")
+	squash := cmd.Bool([]string{"-squash"}, false, "Squash newly built layers into a single new layer")
 	dockerfileName := cmd.String([]string{"f", "-file"}, "", "Name of the Dockerfile (Default is 'PATH/Dockerfile')")
 	flMemoryString := cmd.String([]string{"m", "-memory"}, "", "Memory limit")
 	flMemorySwap := cmd.String([]string{"-memory-swap"}, "", "Swap limit equal to memory plus swap: '-1' to enable unlimited swap")
@@ -235,6 +236,7 @@ func (cli *DockerCli) CmdBuild(args ...string) error {
 		BuildArgs:      runconfigopts.ConvertKVStringsToMap(flBuildArg.GetAll()),
 		AuthConfigs:    cli.retrieveAuthConfigs(),
 		Labels:         runconfigopts.ConvertKVStringsToMap(flLabels.GetAll()),
+		Squash:         *squash,
 	}
 
 	response, err := cli.client.ImageBuild(ctx, body, options)
@@ -21,6 +21,7 @@ func (cli *DockerCli) CmdCommit(args ...string) error {
 	flPause := cmd.Bool([]string{"p", "-pause"}, true, "Pause container during commit")
 	flComment := cmd.String([]string{"m", "-message"}, "", "Commit message")
 	flAuthor := cmd.String([]string{"a", "-author"}, "", "Author (e.g., \"John Hannibal Smith <hannibal@a-team.com>\")")
+	flSquash := cmd.Bool([]string{"-squash"}, false, "Squash the layers produced after the base image into a single layer")
 	flChanges := opts.NewListOpts(nil)
 	cmd.Var(&flChanges, []string{"c", "-change"}, "Apply Dockerfile instruction to the created image")
 	// FIXME: --run is deprecated, it will be replaced with inline Dockerfile commands.
@@ -49,6 +50,7 @@ func (cli *DockerCli) CmdCommit(args ...string) error {
 		Author:    *flAuthor,
 		Changes:   flChanges.GetAll(),
 		Pause:     *flPause,
+		Squash:    *flSquash,
 		Config:    config,
 	}
 
@@ -52,6 +52,7 @@ func (s *imageRouter) postCommit(ctx context.Context, w http.ResponseWriter, r *
 			Comment:      r.Form.Get("comment"),
 			Config:       c,
 			MergeConfigs: true,
+			Squash:       httputils.BoolValue(r, "squash"),
 		},
 		Changes: r.Form["changes"],
 	}
@@ -183,7 +184,7 @@ func (s *imageRouter) getImagesGet(ctx context.Context, w http.ResponseWriter, r
 		names = r.Form["names"]
 	}
 
-	if err := s.backend.ExportImage(names, output); err != nil {
+	if err := s.backend.ExportImage(names, r.Form.Get("format"), output); err != nil {
 		if !output.Flushed() {
 			return err
 		}
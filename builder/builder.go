@@ -129,6 +129,9 @@ type Backend interface {
 	ContainerWait(containerID string, timeout time.Duration) (int, error)
 	// ContainerUpdateCmdOnBuild updates container.Path and container.Args
 	ContainerUpdateCmdOnBuild(containerID string, cmd []string) error
+	// SquashImage squashes the layers of image `id` produced after
+	// image `parent` into a single layer and returns the new image ID.
+	SquashImage(id, parent string) (string, error)
 
 	// ContainerCopy copies/extracts a source FileInfo to a destination path inside a container
 	// specified by a container object.
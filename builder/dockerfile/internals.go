@@ -395,8 +395,10 @@ func containsWildcards(name string) bool {
 }
 
 func (b *Builder) processImageFrom(img builder.Image) error {
+	b.baseImage = ""
 	if img != nil {
 		b.image = img.ImageID()
+		b.baseImage = b.image
 
 		if img.RunConfig() != nil {
 			b.runConfig = img.RunConfig()
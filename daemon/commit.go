@@ -7,6 +7,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/Sirupsen/logrus"
 	"github.com/docker/docker/api/types/backend"
 	"github.com/docker/docker/builder/dockerfile"
 	"github.com/docker/docker/container"
@@ -205,6 +206,19 @@ func (daemon *Daemon) Commit(name string, c *backend.ContainerCommitConfig) (str
 		}
 	}
 
+	if c.Squash {
+		if base := daemon.baseImage(container.ImageID); base != container.ImageID {
+			squashedID, err := daemon.SquashImage(id.String(), base.String())
+			if err != nil {
+				return "", err
+			}
+			if _, err := daemon.imageStore.Delete(id); err != nil {
+				logrus.Warnf("failed to remove intermediate image %s after squash: %v", id, err)
+			}
+			id = image.ID(squashedID)
+		}
+	}
+
 	if c.Repo != "" {
 		newTag, err := reference.WithName(c.Repo) // todo: should move this to API layer
 		if err != nil {
@@ -0,0 +1,119 @@
+package daemon
+
+import (
+	"encoding/json"
+	"fmt"
+	"runtime"
+	"time"
+
+	"github.com/docker/docker/image"
+	"github.com/docker/docker/layer"
+)
+
+// SquashImage creates a new image with the diff of the specified image and
+// the specified parent. The new image contains the layers of the parent plus
+// one extra layer holding the changes of all the layers in between. The
+// history entries of the squashed layers are kept, but marked as empty
+// layers. The existing image is not removed. If no parent is specified, all
+// the layers of the image are merged into a single layer.
+func (daemon *Daemon) SquashImage(id, parent string) (string, error) {
+	if runtime.GOOS == "windows" {
+		return "", fmt.Errorf("squashing images is not supported on Windows")
+	}
+	if id == parent {
+		return id, nil
+	}
+
+	img, err := daemon.imageStore.Get(image.ID(id))
+	if err != nil {
+		return "", err
+	}
+
+	parentImg := &image.Image{RootFS: image.NewRootFS()}
+	if parent != "" {
+		parentImg, err = daemon.imageStore.Get(image.ID(parent))
+		if err != nil {
+			return "", fmt.Errorf("error getting specified parent image: %v", err)
+		}
+	}
+	parentChainID := parentImg.RootFS.ChainID()
+
+	l, err := daemon.layerStore.Get(img.RootFS.ChainID())
+	if err != nil {
+		return "", fmt.Errorf("error getting image layer: %v", err)
+	}
+	defer layer.ReleaseAndLog(daemon.layerStore, l)
+
+	ts, err := l.TarStreamFrom(parentChainID)
+	if err != nil {
+		return "", fmt.Errorf("error getting tar stream to parent: %v", err)
+	}
+	defer ts.Close()
+
+	newL, err := daemon.layerStore.Register(ts, parentChainID)
+	if err != nil {
+		return "", fmt.Errorf("error registering layer: %v", err)
+	}
+	defer layer.ReleaseAndLog(daemon.layerStore, newL)
+
+	newImage := *img
+	newImage.Parent = ""
+	newImage.V1Image.ID = ""
+	newImage.V1Image.Parent = ""
+
+	rootFS := *parentImg.RootFS
+	rootFS.DiffIDs = append([]layer.DiffID(nil), parentImg.RootFS.DiffIDs...)
+
+	now := time.Now().UTC()
+	h := image.History{
+		Created:    now,
+		Comment:    fmt.Sprintf("merge %s to %s", id, parent),
+		EmptyLayer: true,
+	}
+	if diffID := newL.DiffID(); diffID != layer.DigestSHA256EmptyTar {
+		h.EmptyLayer = false
+		rootFS.Append(diffID)
+	}
+	newImage.RootFS = &rootFS
+
+	newImage.History = make([]image.History, 0, len(img.History)+1)
+	for i, hi := range img.History {
+		if i >= len(parentImg.History) {
+			hi.EmptyLayer = true
+		}
+		newImage.History = append(newImage.History, hi)
+	}
+	newImage.History = append(newImage.History, h)
+	newImage.Created = now
+
+	config, err := json.Marshal(&newImage)
+	if err != nil {
+		return "", err
+	}
+
+	newImgID, err := daemon.imageStore.Create(config)
+	if err != nil {
+		return "", err
+	}
+
+	if parent != "" {
+		if err := daemon.imageStore.SetParent(newImgID, image.ID(parent)); err != nil {
+			return "", err
+		}
+	}
+
+	return newImgID.String(), nil
+}
+
+// baseImage returns the first ancestor of the given image that was not
+// produced locally by a build or a commit, that is the first image in its
+// chain without a parent.
+func (daemon *Daemon) baseImage(id image.ID) image.ID {
+	for {
+		parent, err := daemon.imageStore.GetParent(id)
+		if err != nil || parent == "" {
+			return id
+		}
+		id = parent
+	}
+}
@@ -30,6 +30,10 @@ func (ml *mockLayer) TarStream() (io.ReadCloser, error) {
 	return ioutil.NopCloser(bytes.NewBuffer(ml.layerData.Bytes())), nil
 }
 
+func (ml *mockLayer) TarStreamFrom(layer.ChainID) (io.ReadCloser, error) {
+	return nil, errors.New("not implemented")
+}
+
 func (ml *mockLayer) ChainID() layer.ChainID {
 	return ml.chainID
 }
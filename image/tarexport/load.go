@@ -41,6 +41,14 @@ func (l *tarexporter) Load(inTar io.ReadCloser, outStream io.Writer, quiet bool)
 	if err := chrootarchive.Untar(inTar, tmpDir, nil); err != nil {
 		return err
 	}
+	// load in OCI image layout mode if the layout file is present
+	layoutPath, err := safePath(tmpDir, ociLayoutFileName)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(layoutPath); err == nil {
+		return l.ociLoad(tmpDir, outStream, progressOutput)
+	}
 	// read manifest, if no file then load in legacy mode
 	manifestPath, err := safePath(tmpDir, manifestFileName)
 	if err != nil {
@@ -71,40 +79,17 @@ func (l *tarexporter) Load(inTar io.ReadCloser, outStream io.Writer, quiet bool)
 		if err != nil {
 			return err
 		}
-		img, err := image.NewFromJSON(config)
-		if err != nil {
-			return err
-		}
-		var rootFS image.RootFS
-		rootFS = *img.RootFS
-		rootFS.DiffIDs = nil
 
-		if expected, actual := len(m.Layers), len(img.RootFS.DiffIDs); expected != actual {
-			return fmt.Errorf("invalid manifest, layers length mismatch: expected %q, got %q", expected, actual)
-		}
-
-		for i, diffID := range img.RootFS.DiffIDs {
-			layerPath, err := safePath(tmpDir, m.Layers[i])
+		var layerPaths []string
+		for _, layerName := range m.Layers {
+			layerPath, err := safePath(tmpDir, layerName)
 			if err != nil {
 				return err
 			}
-			r := rootFS
-			r.Append(diffID)
-			newLayer, err := l.ls.Get(r.ChainID())
-			if err != nil {
-				newLayer, err = l.loadLayer(layerPath, rootFS, diffID.String(), progressOutput)
-				if err != nil {
-					return err
-				}
-			}
-			defer layer.ReleaseAndLog(l.ls, newLayer)
-			if expected, actual := diffID, newLayer.DiffID(); expected != actual {
-				return fmt.Errorf("invalid diffID for layer %d: expected %q, got %q", i, expected, actual)
-			}
-			rootFS.Append(diffID)
+			layerPaths = append(layerPaths, layerPath)
 		}
 
-		imgID, err := l.is.Create(config)
+		imgID, err := l.loadImage(config, layerPaths, progressOutput)
 		if err != nil {
 			return err
 		}
@@ -136,6 +121,41 @@ func (l *tarexporter) Load(inTar io.ReadCloser, outStream io.Writer, quiet bool)
 	return nil
 }
 
+// loadImage creates the image described by config, registering its layers
+// from the given layer files when they are not present in the layer store.
+func (l *tarexporter) loadImage(config []byte, layerPaths []string, progressOutput progress.Output) (image.ID, error) {
+	img, err := image.NewFromJSON(config)
+	if err != nil {
+		return "", err
+	}
+	var rootFS image.RootFS
+	rootFS = *img.RootFS
+	rootFS.DiffIDs = nil
+
+	if expected, actual := len(layerPaths), len(img.RootFS.DiffIDs); expected != actual {
+		return "", fmt.Errorf("invalid manifest, layers length mismatch: expected %d, got %d", expected, actual)
+	}
+
+	for i, diffID := range img.RootFS.DiffIDs {
+		r := rootFS
+		r.Append(diffID)
+		newLayer, err := l.ls.Get(r.ChainID())
+		if err != nil {
+			newLayer, err = l.loadLayer(layerPaths[i], rootFS, diffID.String(), progressOutput)
+			if err != nil {
+				return "", err
+			}
+		}
+		defer layer.ReleaseAndLog(l.ls, newLayer)
+		if expected, actual := diffID, newLayer.DiffID(); expected != actual {
+			return "", fmt.Errorf("invalid diffID for layer %d: expected %q, got %q", i, expected, actual)
+		}
+		rootFS.Append(diffID)
+	}
+
+	return l.is.Create(config)
+}
+
 func (l *tarexporter) setParentID(id, parentID image.ID) error {
 	img, err := l.is.Get(id)
 	if err != nil {
@@ -0,0 +1,384 @@
+package tarexport
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/docker/distribution/digest"
+	"github.com/docker/docker/image"
+	"github.com/docker/docker/layer"
+	"github.com/docker/docker/pkg/archive"
+	"github.com/docker/docker/pkg/progress"
+	"github.com/docker/docker/pkg/system"
+	"github.com/docker/docker/reference"
+)
+
+const (
+	ociImageLayoutVersion = "1.0.0"
+	ociRefNameAnnotation  = "org.opencontainers.image.ref.name"
+
+	ociMediaTypeIndex     = "application/vnd.oci.image.index.v1+json"
+	ociMediaTypeManifest  = "application/vnd.oci.image.manifest.v1+json"
+	ociMediaTypeConfig    = "application/vnd.oci.image.config.v1+json"
+	ociMediaTypeLayer     = "application/vnd.oci.image.layer.v1.tar"
+	ociMediaTypeLayerGzip = "application/vnd.oci.image.layer.v1.tar+gzip"
+)
+
+type ociLayout struct {
+	Version string `json:"imageLayoutVersion"`
+}
+
+type ociDescriptor struct {
+	MediaType   string            `json:"mediaType"`
+	Digest      digest.Digest     `json:"digest"`
+	Size        int64             `json:"size"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+type ociIndex struct {
+	SchemaVersion int             `json:"schemaVersion"`
+	MediaType     string          `json:"mediaType,omitempty"`
+	Manifests     []ociDescriptor `json:"manifests"`
+}
+
+type ociManifest struct {
+	SchemaVersion int             `json:"schemaVersion"`
+	MediaType     string          `json:"mediaType,omitempty"`
+	Config        ociDescriptor   `json:"config"`
+	Layers        []ociDescriptor `json:"layers"`
+}
+
+type ociExporter struct {
+	*tarexporter
+}
+
+// NewOCIExporter returns new ImageExporter that saves images in the OCI
+// image layout format. Load accepts both the OCI and the Docker formats.
+func NewOCIExporter(is image.Store, ls layer.Store, rs reference.Store, loggerImgEvent LogImageEvent) image.Exporter {
+	return &ociExporter{
+		tarexporter: &tarexporter{
+			is:             is,
+			ls:             ls,
+			rs:             rs,
+			loggerImgEvent: loggerImgEvent,
+		},
+	}
+}
+
+type ociSaveSession struct {
+	*tarexporter
+	outDir     string
+	images     map[image.ID]*imageDescriptor
+	savedBlobs map[digest.Digest]int64
+}
+
+func (l *ociExporter) Save(names []string, outStream io.Writer) error {
+	images, err := l.parseNames(names)
+	if err != nil {
+		return err
+	}
+
+	return (&ociSaveSession{tarexporter: l.tarexporter, images: images}).save(outStream)
+}
+
+func (s *ociSaveSession) save(outStream io.Writer) error {
+	s.savedBlobs = make(map[digest.Digest]int64)
+
+	tempDir, err := ioutil.TempDir("", "docker-export-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tempDir)
+
+	s.outDir = tempDir
+	if err := os.MkdirAll(filepath.Join(tempDir, ociBlobsDirName, string(digest.Canonical)), 0755); err != nil {
+		return err
+	}
+
+	index := ociIndex{
+		SchemaVersion: 2,
+		MediaType:     ociMediaTypeIndex,
+		Manifests:     []ociDescriptor{},
+	}
+
+	for id, imageDescr := range s.images {
+		desc, err := s.saveImage(id)
+		if err != nil {
+			return err
+		}
+
+		if len(imageDescr.refs) == 0 {
+			index.Manifests = append(index.Manifests, desc)
+		}
+		for _, ref := range imageDescr.refs {
+			refDesc := desc
+			refDesc.Annotations = map[string]string{
+				ociRefNameAnnotation: ref.String(),
+			}
+			index.Manifests = append(index.Manifests, refDesc)
+		}
+
+		s.tarexporter.loggerImgEvent.LogImageEvent(id.String(), id.String(), "save")
+	}
+
+	if err := writeJSONFile(filepath.Join(tempDir, ociLayoutFileName), ociLayout{Version: ociImageLayoutVersion}); err != nil {
+		return err
+	}
+	if err := writeJSONFile(filepath.Join(tempDir, ociIndexFileName), index); err != nil {
+		return err
+	}
+
+	fs, err := archive.Tar(tempDir, archive.Uncompressed)
+	if err != nil {
+		return err
+	}
+	defer fs.Close()
+
+	if _, err := io.Copy(outStream, fs); err != nil {
+		return err
+	}
+	return nil
+}
+
+// saveImage writes the config, layers and manifest blobs of an image and
+// returns the descriptor of its manifest.
+func (s *ociSaveSession) saveImage(id image.ID) (ociDescriptor, error) {
+	img, err := s.is.Get(id)
+	if err != nil {
+		return ociDescriptor{}, err
+	}
+
+	if len(img.RootFS.DiffIDs) == 0 {
+		return ociDescriptor{}, fmt.Errorf("empty export - not implemented")
+	}
+
+	configDesc, err := s.saveBlob(img.RawJSON(), ociMediaTypeConfig)
+	if err != nil {
+		return ociDescriptor{}, err
+	}
+
+	var layers []ociDescriptor
+	for i, diffID := range img.RootFS.DiffIDs {
+		rootFS := *img.RootFS
+		rootFS.DiffIDs = rootFS.DiffIDs[:i+1]
+		layerDesc, err := s.saveLayer(rootFS.ChainID(), diffID)
+		if err != nil {
+			return ociDescriptor{}, err
+		}
+		layers = append(layers, layerDesc)
+	}
+
+	manifest, err := json.Marshal(ociManifest{
+		SchemaVersion: 2,
+		MediaType:     ociMediaTypeManifest,
+		Config:        configDesc,
+		Layers:        layers,
+	})
+	if err != nil {
+		return ociDescriptor{}, err
+	}
+	return s.saveBlob(manifest, ociMediaTypeManifest)
+}
+
+func (s *ociSaveSession) saveBlob(b []byte, mediaType string) (ociDescriptor, error) {
+	dgst := digest.FromBytes(b)
+	desc := ociDescriptor{
+		MediaType: mediaType,
+		Digest:    dgst,
+		Size:      int64(len(b)),
+	}
+	if _, exists := s.savedBlobs[dgst]; exists {
+		return desc, nil
+	}
+
+	blobPath := s.blobPath(dgst)
+	if err := ioutil.WriteFile(blobPath, b, 0644); err != nil {
+		return ociDescriptor{}, err
+	}
+	if err := system.Chtimes(blobPath, time.Unix(0, 0), time.Unix(0, 0)); err != nil {
+		return ociDescriptor{}, err
+	}
+	s.savedBlobs[dgst] = desc.Size
+	return desc, nil
+}
+
+// saveLayer writes the uncompressed tar stream of a layer as a blob. The
+// digest of the blob is the DiffID of the layer.
+func (s *ociSaveSession) saveLayer(id layer.ChainID, diffID layer.DiffID) (ociDescriptor, error) {
+	dgst := digest.Digest(diffID)
+	if size, exists := s.savedBlobs[dgst]; exists {
+		return ociDescriptor{MediaType: ociMediaTypeLayer, Digest: dgst, Size: size}, nil
+	}
+
+	l, err := s.ls.Get(id)
+	if err != nil {
+		return ociDescriptor{}, err
+	}
+	defer layer.ReleaseAndLog(s.ls, l)
+
+	arch, err := l.TarStream()
+	if err != nil {
+		return ociDescriptor{}, err
+	}
+	defer arch.Close()
+
+	blobPath := s.blobPath(dgst)
+	tarFile, err := os.Create(blobPath)
+	if err != nil {
+		return ociDescriptor{}, err
+	}
+	size, err := io.Copy(tarFile, arch)
+	tarFile.Close()
+	if err != nil {
+		return ociDescriptor{}, err
+	}
+	if err := system.Chtimes(blobPath, time.Unix(0, 0), time.Unix(0, 0)); err != nil {
+		return ociDescriptor{}, err
+	}
+
+	s.savedBlobs[dgst] = size
+	return ociDescriptor{MediaType: ociMediaTypeLayer, Digest: dgst, Size: size}, nil
+}
+
+func (s *ociSaveSession) blobPath(dgst digest.Digest) string {
+	return filepath.Join(s.outDir, ociBlobsDirName, dgst.Algorithm().String(), dgst.Hex())
+}
+
+func writeJSONFile(path string, v interface{}) error {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	if err := json.NewEncoder(f).Encode(v); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return system.Chtimes(path, time.Unix(0, 0), time.Unix(0, 0))
+}
+
+func (l *tarexporter) ociLoad(tmpDir string, outStream io.Writer, progressOutput progress.Output) error {
+	layoutPath, err := safePath(tmpDir, ociLayoutFileName)
+	if err != nil {
+		return err
+	}
+	layoutJSON, err := ioutil.ReadFile(layoutPath)
+	if err != nil {
+		return err
+	}
+	var layout ociLayout
+	if err := json.Unmarshal(layoutJSON, &layout); err != nil {
+		return err
+	}
+	if layout.Version != ociImageLayoutVersion {
+		return fmt.Errorf("unsupported OCI image layout version %q", layout.Version)
+	}
+
+	indexPath, err := safePath(tmpDir, ociIndexFileName)
+	if err != nil {
+		return err
+	}
+	indexJSON, err := ioutil.ReadFile(indexPath)
+	if err != nil {
+		return err
+	}
+	var index ociIndex
+	if err := json.Unmarshal(indexJSON, &index); err != nil {
+		return err
+	}
+
+	loaded := make(map[digest.Digest]image.ID)
+	for _, desc := range index.Manifests {
+		if desc.MediaType != ociMediaTypeManifest {
+			return fmt.Errorf("unsupported manifest media type %q", desc.MediaType)
+		}
+
+		imgID, ok := loaded[desc.Digest]
+		if !ok {
+			imgID, err = l.ociLoadImage(tmpDir, desc, progressOutput)
+			if err != nil {
+				return err
+			}
+			loaded[desc.Digest] = imgID
+			l.loggerImgEvent.LogImageEvent(imgID.String(), imgID.String(), "load")
+		}
+
+		refName := desc.Annotations[ociRefNameAnnotation]
+		if refName == "" {
+			continue
+		}
+		named, err := reference.ParseNamed(refName)
+		if err != nil {
+			return err
+		}
+		ref, ok := named.(reference.NamedTagged)
+		if !ok {
+			return fmt.Errorf("invalid tag %q", refName)
+		}
+		l.setLoadedTag(ref, imgID, outStream)
+	}
+
+	return nil
+}
+
+func (l *tarexporter) ociLoadImage(tmpDir string, desc ociDescriptor, progressOutput progress.Output) (image.ID, error) {
+	manifestJSON, err := readOCIBlob(tmpDir, desc)
+	if err != nil {
+		return "", err
+	}
+	var manifest ociManifest
+	if err := json.Unmarshal(manifestJSON, &manifest); err != nil {
+		return "", err
+	}
+
+	config, err := readOCIBlob(tmpDir, manifest.Config)
+	if err != nil {
+		return "", err
+	}
+
+	var layerPaths []string
+	for _, layerDesc := range manifest.Layers {
+		switch layerDesc.MediaType {
+		case ociMediaTypeLayer, ociMediaTypeLayerGzip:
+		default:
+			return "", fmt.Errorf("unsupported layer media type %q", layerDesc.MediaType)
+		}
+		layerPath, err := ociBlobPath(tmpDir, layerDesc.Digest)
+		if err != nil {
+			return "", err
+		}
+		layerPaths = append(layerPaths, layerPath)
+	}
+
+	return l.loadImage(config, layerPaths, progressOutput)
+}
+
+func ociBlobPath(dir string, dgst digest.Digest) (string, error) {
+	if err := dgst.Validate(); err != nil {
+		return "", err
+	}
+	return safePath(dir, filepath.Join(ociBlobsDirName, dgst.Algorithm().String(), dgst.Hex()))
+}
+
+// readOCIBlob reads the blob referenced by desc and verifies its digest.
+func readOCIBlob(dir string, desc ociDescriptor) ([]byte, error) {
+	blobPath, err := ociBlobPath(dir, desc.Digest)
+	if err != nil {
+		return nil, err
+	}
+	b, err := ioutil.ReadFile(blobPath)
+	if err != nil {
+		return nil, err
+	}
+	if actual := digest.FromBytes(b); actual != desc.Digest {
+		return nil, fmt.Errorf("invalid blob %s: digest mismatch, got %s", desc.Digest, actual)
+	}
+	return b, nil
+}
@@ -12,6 +12,17 @@ const (
 	legacyConfigFileName       = "json"
 	legacyVersionFileName      = "VERSION"
 	legacyRepositoriesFileName = "repositories"
+	ociLayoutFileName          = "oci-layout"
+	ociIndexFileName           = "index.json"
+	ociBlobsDirName            = "blobs"
+)
+
+const (
+	// FormatDocker is the Docker image archive format, with a manifest.json
+	// and legacy v1 image directories.
+	FormatDocker = "docker"
+	// FormatOCI is the OCI image layout format.
+	FormatOCI = "oci"
 )
 
 type manifestItem struct {
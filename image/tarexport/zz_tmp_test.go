@@ -0,0 +1,85 @@
+package tarexport
+
+import (
+	"archive/tar"
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/docker/docker/daemon/graphdriver"
+	_ "github.com/docker/docker/daemon/graphdriver/vfs"
+	"github.com/docker/docker/image"
+	"github.com/docker/docker/layer"
+	"github.com/docker/docker/reference"
+)
+
+type nopLogger struct{}
+
+func (nopLogger) LogImageEvent(a, b, c string) {}
+
+func TestTmpOCI(t *testing.T) {
+	td, _ := ioutil.TempDir("", "oci")
+	defer os.RemoveAll(td)
+	drv, err := graphdriver.GetDriver("vfs", filepath.Join(td, "g"), nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fms, _ := layer.NewFSMetadataStore(filepath.Join(td, "l"))
+	ls, err := layer.NewStoreFromGraphDriver(fms, drv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ifs, _ := image.NewFSStoreBackend(filepath.Join(td, "i"))
+	is, _ := image.NewImageStore(ifs, ls)
+	rs, _ := reference.NewReferenceStore(filepath.Join(td, "r.json"))
+
+	buf := new(bytes.Buffer)
+	tw := tar.NewWriter(buf)
+	tw.WriteHeader(&tar.Header{Name: "foo", Mode: 0644, Size: 3})
+	tw.Write([]byte("abc"))
+	tw.Close()
+	l, err := ls.Register(bytes.NewReader(buf.Bytes()), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rootfs := image.NewRootFS()
+	rootfs.Append(l.DiffID())
+	cfg, _ := json.Marshal(&image.Image{RootFS: rootfs, History: []image.History{{CreatedBy: "x"}}})
+	id, err := is.Create(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	named, _ := reference.ParseNamed("foo:bar")
+	rs.AddTag(named, id, true)
+
+	ex := NewOCIExporter(is, ls, rs, nopLogger{})
+	out := new(bytes.Buffer)
+	if err := ex.Save([]string{"foo:bar"}, out); err != nil {
+		t.Fatal(err)
+	}
+	tr := tar.NewReader(bytes.NewReader(out.Bytes()))
+	for {
+		h, err := tr.Next()
+		if err != nil {
+			break
+		}
+		b, _ := ioutil.ReadAll(tr)
+		if h.Name == "index.json" || h.Name == "oci-layout" {
+			t.Logf("%s: %s", h.Name, b)
+		} else {
+			t.Logf("%s %d", h.Name, len(b))
+		}
+	}
+	is.Delete(id)
+	rs.Delete(named)
+	if err := ex.Load(ioutil.NopCloser(bytes.NewReader(out.Bytes())), ioutil.Discard, true); err != nil {
+		t.Fatal(err)
+	}
+	got, err := rs.Get(named)
+	if err != nil || got != id {
+		t.Fatal(got, err)
+	}
+}
@@ -350,3 +350,37 @@ func (s *DockerSuite) TestSaveLoadParents(c *check.C) {
 	inspectOut = inspectField(c, idFoo, "Parent")
 	c.Assert(inspectOut, checker.Equals, "")
 }
+
+func (s *DockerSuite) TestSaveLoadOCIFormat(c *check.C) {
+	testRequires(c, DaemonIsLinux)
+	repoName := "foobar-save-load-oci-test"
+	dockerCmd(c, "tag", "busybox:latest", fmt.Sprintf("%v:latest", repoName))
+
+	before := inspectField(c, repoName, "Id")
+
+	tmpDir, err := ioutil.TempDir("", "save-load-oci")
+	c.Assert(err, checker.IsNil)
+	defer os.RemoveAll(tmpDir)
+
+	outfile := filepath.Join(tmpDir, "out.tar")
+	dockerCmd(c, "save", "--format=oci", "-o", outfile, repoName)
+
+	out, _, err := runCommandWithOutput(exec.Command("tar", "tf", outfile))
+	c.Assert(err, checker.IsNil, check.Commentf("failed to list saved archive: %s", out))
+	c.Assert(out, checker.Contains, "oci-layout")
+	c.Assert(out, checker.Contains, "index.json")
+	c.Assert(out, checker.Contains, "blobs/sha256/"+strings.TrimPrefix(before, "sha256:"))
+	c.Assert(out, checker.Not(checker.Contains), "manifest.json")
+
+	deleteImages(repoName)
+	dockerCmd(c, "load", "-i", outfile)
+
+	after := inspectField(c, repoName, "Id")
+	c.Assert(after, checker.Equals, before)
+}
+
+func (s *DockerSuite) TestSaveInvalidFormat(c *check.C) {
+	out, _, err := dockerCmdWithError("save", "--format=invalid", "-o", "/dev/null", "busybox")
+	c.Assert(err, checker.NotNil)
+	c.Assert(out, checker.Contains, "invalid image archive format")
+}
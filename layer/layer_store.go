@@ -33,6 +33,9 @@ type layerStore struct {
 
 	mounts map[string]*mountedLayer
 	mountL sync.Mutex
+
+	uidMaps []idtools.IDMap
+	gidMaps []idtools.IDMap
 }
 
 // StoreOptions are the options used to create a new Store instance
@@ -63,18 +66,24 @@ func NewStoreFromOptions(options StoreOptions) (Store, error) {
 		return nil, err
 	}
 
-	return NewStoreFromGraphDriver(fms, driver)
+	return newStoreFromGraphDriver(fms, driver, options.UIDMaps, options.GIDMaps)
 }
 
 // NewStoreFromGraphDriver creates a new Store instance using the provided
 // metadata store and graph driver. The metadata store will be used to restore
 // the Store.
 func NewStoreFromGraphDriver(store MetadataStore, driver graphdriver.Driver) (Store, error) {
+	return newStoreFromGraphDriver(store, driver, nil, nil)
+}
+
+func newStoreFromGraphDriver(store MetadataStore, driver graphdriver.Driver, uidMaps, gidMaps []idtools.IDMap) (Store, error) {
 	ls := &layerStore{
 		store:    store,
 		driver:   driver,
 		layerMap: map[ChainID]*roLayer{},
 		mounts:   map[string]*mountedLayer{},
+		uidMaps:  uidMaps,
+		gidMaps:  gidMaps,
 	}
 
 	ids, mounts, err := store.List()
@@ -766,3 +766,46 @@ func TestTarStreamVerification(t *testing.T) {
 		t.Fatalf("wrong error returned from tarstream: %q", err)
 	}
 }
+
+func TestTarStreamFrom(t *testing.T) {
+	// TODO Windows: Figure out why this is failing
+	if runtime.GOOS == "windows" {
+		t.Skip("Failing on Windows")
+	}
+	ls, _, cleanup := newTestStore(t)
+	defer cleanup()
+
+	layer1, err := createLayer(ls, "", initWithFiles(newTestFile("/base", []byte("base"), 0644)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	layer2, err := createLayer(ls, layer1.ChainID(), initWithFiles(newTestFile("/foo", []byte("abc"), 0644)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	layer3, err := createLayer(ls, layer2.ChainID(), initWithFiles(newTestFile("/bar", []byte("def"), 0644)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ts, err := layer3.TarStreamFrom(layer1.ChainID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ts.Close()
+
+	squashed, err := ls.Register(ts, layer1.ChainID())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected, err := tarFromFiles(newTestFile("/bar", []byte("def"), 0644), newTestFile("/foo", []byte("abc"), 0644))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertLayerDiff(t, expected, squashed)
+
+	if _, err := layer1.TarStreamFrom(layer3.ChainID()); err == nil {
+		t.Fatal("expected error getting tar stream from a layer which is not a parent")
+	}
+}
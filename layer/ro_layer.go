@@ -5,6 +5,7 @@ import (
 	"io"
 
 	"github.com/docker/distribution/digest"
+	"github.com/docker/docker/daemon/graphdriver"
 )
 
 type roLayer struct {
@@ -41,6 +42,25 @@ func (rl *roLayer) TarStream() (io.ReadCloser, error) {
 	return rc, nil
 }
 
+// TarStreamFrom returns a tar archive stream of the changes between this
+// layer and the given parent in its chain. An empty parent returns the
+// contents of the whole chain.
+func (rl *roLayer) TarStreamFrom(parent ChainID) (io.ReadCloser, error) {
+	var parentCacheID string
+	for pl := rl.parent; pl != nil; pl = pl.parent {
+		if pl.chainID == parent {
+			parentCacheID = pl.cacheID
+			break
+		}
+	}
+
+	if parent != ChainID("") && parentCacheID == "" {
+		return nil, fmt.Errorf("layer ID '%s' is not a parent of the specified layer: cannot provide diff to non-parent", parent)
+	}
+	driver := graphdriver.NewNaiveDiffDriver(rl.layerStore.driver, rl.layerStore.uidMaps, rl.layerStore.gidMaps)
+	return driver.Diff(rl.cacheID, parentCacheID)
+}
+
 func (rl *roLayer) ChainID() ChainID {
 	return rl.chainID
 }
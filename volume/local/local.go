@@ -9,6 +9,8 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 	"sync"
 
 	"github.com/Sirupsen/logrus"
@@ -16,6 +18,7 @@ import (
 	"github.com/docker/docker/pkg/mount"
 	"github.com/docker/docker/utils"
 	"github.com/docker/docker/volume"
+	"github.com/docker/go-units"
 )
 
 // VolumeDataPathName is the name of the directory where the volume data is stored.
@@ -88,10 +91,12 @@ func New(scope string, rootUID, rootGID int) (*Root, error) {
 			path:       r.DataPath(name),
 		}
 		r.volumes[name] = v
-		if b, err := ioutil.ReadFile(filepath.Join(name, "opts.json")); err == nil {
-			if err := json.Unmarshal(b, v.opts); err != nil {
+		if b, err := ioutil.ReadFile(filepath.Join(rootDirectory, name, "opts.json")); err == nil {
+			opts := optsConfig{}
+			if err := json.Unmarshal(b, &opts); err != nil {
 				return nil, err
 			}
+			v.opts = &opts
 
 			// unmount anything that may still be mounted (for example, from an unclean shutdown)
 			for _, info := range mountInfos {
@@ -185,7 +190,10 @@ func (r *Root) Create(name string, opts map[string]string) (volume.Volume, error
 		if err != nil {
 			return nil, err
 		}
-		if err = ioutil.WriteFile(filepath.Join(filepath.Dir(path), "opts.json"), b, 600); err != nil {
+		if err = ioutil.WriteFile(filepath.Join(filepath.Dir(path), "opts.json"), b, 0600); err != nil {
+			return nil, err
+		}
+		if err = v.setup(); err != nil {
 			return nil, err
 		}
 	}
@@ -290,7 +298,7 @@ func (v *localVolume) Path() string {
 func (v *localVolume) Mount(id string) (string, error) {
 	v.m.Lock()
 	defer v.m.Unlock()
-	if v.opts != nil {
+	if v.needsMount() {
 		if !v.active.mounted {
 			if err := v.mount(); err != nil {
 				return "", err
@@ -306,7 +314,7 @@ func (v *localVolume) Mount(id string) (string, error) {
 func (v *localVolume) Unmount(id string) error {
 	v.m.Lock()
 	defer v.m.Unlock()
-	if v.opts != nil {
+	if v.needsMount() {
 		v.active.count--
 		if v.active.count == 0 {
 			if err := mount.Unmount(v.path); err != nil {
@@ -325,9 +333,77 @@ func validateOpts(opts map[string]string) error {
 			return validationError{fmt.Errorf("invalid option key: %q", opt)}
 		}
 	}
+
+	if val, ok := opts["size"]; ok {
+		if _, err := parseSize(val); err != nil {
+			return err
+		}
+		if opts["type"] != "tmpfs" && opts["device"] != "" {
+			return validationError{fmt.Errorf("size option is only supported for tmpfs volumes or volumes without a device")}
+		}
+		if strings.Contains(opts["o"], "size=") {
+			return validationError{fmt.Errorf("size option conflicts with size in mount options: %q", opts["o"])}
+		}
+	}
+	for _, opt := range []string{"uid", "gid"} {
+		if val, ok := opts[opt]; ok {
+			if _, err := parseID(opt, val); err != nil {
+				return err
+			}
+		}
+	}
+	if val, ok := opts["mode"]; ok {
+		if _, err := parseMode(val); err != nil {
+			return err
+		}
+	}
+
+	if opts["device"] == "" && opts["type"] != "tmpfs" && (opts["type"] != "" || opts["o"] != "") {
+		return validationError{fmt.Errorf("missing device in volume options")}
+	}
 	return nil
 }
 
+// parseSize parses a human readable size (e.g. "10G") into a number of bytes.
+func parseSize(val string) (int64, error) {
+	size, err := units.RAMInBytes(val)
+	if err != nil {
+		return 0, validationError{fmt.Errorf("invalid size option %q: %v", val, err)}
+	}
+	if size <= 0 {
+		return 0, validationError{fmt.Errorf("invalid size option %q: size must be positive", val)}
+	}
+	return size, nil
+}
+
+// parseID parses a uid or gid option value.
+func parseID(opt, val string) (int, error) {
+	id, err := strconv.Atoi(val)
+	if err != nil || id < 0 {
+		return 0, validationError{fmt.Errorf("invalid %s option %q: must be a non-negative integer", opt, val)}
+	}
+	return id, nil
+}
+
+// parseMode parses an octal permission mode (e.g. "0755") for the volume root.
+func parseMode(val string) (os.FileMode, error) {
+	mode, err := strconv.ParseUint(val, 8, 32)
+	if err != nil || mode&^07777 != 0 {
+		return 0, validationError{fmt.Errorf("invalid mode option %q: must be an octal permission mode", val)}
+	}
+	perm := os.FileMode(mode).Perm()
+	if mode&01000 != 0 {
+		perm |= os.ModeSticky
+	}
+	if mode&02000 != 0 {
+		perm |= os.ModeSetgid
+	}
+	if mode&04000 != 0 {
+		perm |= os.ModeSetuid
+	}
+	return perm, nil
+}
+
 func (v *localVolume) Status() map[string]interface{} {
 	return nil
 }
@@ -247,3 +247,80 @@ func TestCreateWithOpts(t *testing.T) {
 		t.Fatal("expected mount to still be active")
 	}
 }
+
+func TestValidateOpts(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip()
+	}
+
+	cases := []struct {
+		opts  map[string]string
+		valid bool
+	}{
+		{map[string]string{"type": "tmpfs", "size": "10m"}, true},
+		{map[string]string{"size": "1g", "uid": "1000", "gid": "1000", "mode": "0750"}, true},
+		{map[string]string{"uid": "1000"}, true},
+		{map[string]string{"size": "notasize"}, false},
+		{map[string]string{"size": "0"}, false},
+		{map[string]string{"type": "tmpfs", "size": "10m", "o": "size=1m"}, false},
+		{map[string]string{"device": "/dev/sdb", "type": "ext4", "size": "10m"}, false},
+		{map[string]string{"uid": "-1"}, false},
+		{map[string]string{"gid": "root"}, false},
+		{map[string]string{"mode": "0999"}, false},
+		{map[string]string{"mode": "17777"}, false},
+		{map[string]string{"type": "nfs", "o": "addr=1.2.3.4"}, false},
+	}
+
+	for _, c := range cases {
+		err := validateOpts(c.opts)
+		if c.valid && err != nil {
+			t.Fatalf("expected %v to be valid, got %v", c.opts, err)
+		}
+		if !c.valid && err == nil {
+			t.Fatalf("expected %v to be invalid", c.opts)
+		}
+	}
+}
+
+func TestCreateWithOwnership(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip()
+	}
+
+	rootDir, err := ioutil.TempDir("", "local-volume-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(rootDir)
+
+	r, err := New(rootDir, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	vol, err := r.Create("test", map[string]string{"mode": "0710"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fi, err := os.Stat(vol.Path())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fi.Mode().Perm() != 0710 {
+		t.Fatalf("expected volume root to have mode 0710, got %v", fi.Mode().Perm())
+	}
+
+	r, err = New(rootDir, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	v, err := r.Get("test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	lv := v.(*localVolume)
+	if lv.opts == nil || lv.opts.Mode == nil || *lv.opts.Mode != 0710 {
+		t.Fatalf("expected volume options to be restored from disk, got %+v", lv.opts)
+	}
+}
@@ -7,6 +7,7 @@ package local
 
 import (
 	"fmt"
+	"os"
 	"path/filepath"
 	"strings"
 
@@ -20,13 +21,25 @@ var (
 		"type":   true, // specify the filesystem type for mount, e.g. nfs
 		"o":      true, // generic mount options
 		"device": true, // device to mount from
+		"size":   true, // size limit of the volume, e.g. 10G
+		"uid":    true, // owner of the volume root
+		"gid":    true, // group of the volume root
+		"mode":   true, // permissions of the volume root, in octal
 	}
 )
 
+// loopbackFileName is the name of the backing file used to enforce the size
+// of volumes created with a size but without a device.
+const loopbackFileName = "data.img"
+
 type optsConfig struct {
 	MountType   string
 	MountOpts   string
 	MountDevice string
+	Size        int64        `json:",omitempty"`
+	UID         *int         `json:",omitempty"`
+	GID         *int         `json:",omitempty"`
+	Mode        *os.FileMode `json:",omitempty"`
 }
 
 // scopedPath verifies that the path where the volume is located
@@ -58,12 +71,124 @@ func setOpts(v *localVolume, opts map[string]string) error {
 		MountOpts:   opts["o"],
 		MountDevice: opts["device"],
 	}
+	if val, ok := opts["size"]; ok {
+		size, err := parseSize(val)
+		if err != nil {
+			return err
+		}
+		v.opts.Size = size
+	}
+	if val, ok := opts["uid"]; ok {
+		uid, err := parseID("uid", val)
+		if err != nil {
+			return err
+		}
+		v.opts.UID = &uid
+	}
+	if val, ok := opts["gid"]; ok {
+		gid, err := parseID("gid", val)
+		if err != nil {
+			return err
+		}
+		v.opts.GID = &gid
+	}
+	if val, ok := opts["mode"]; ok {
+		mode, err := parseMode(val)
+		if err != nil {
+			return err
+		}
+		v.opts.Mode = &mode
+	}
+	if v.opts.MountType == "tmpfs" && v.opts.MountDevice == "" {
+		v.opts.MountDevice = "tmpfs"
+	}
 	return nil
 }
 
+// needsMount reports whether the volume options require the volume path
+// to be mounted before it is used.
+func (v *localVolume) needsMount() bool {
+	if v.opts == nil {
+		return false
+	}
+	return v.opts.MountDevice != "" || v.opts.Size > 0
+}
+
+// usesLoopback reports whether the size of the volume is enforced through
+// a loopback mounted backing file.
+func (v *localVolume) usesLoopback() bool {
+	return v.opts.Size > 0 && v.opts.MountDevice == ""
+}
+
+// setup prepares the volume after it was created with options. Volumes
+// with a size limit but no device get a formatted backing file, and the
+// root of volumes that are not mounted gets its ownership applied.
+func (v *localVolume) setup() error {
+	if v.opts == nil {
+		return nil
+	}
+	if v.usesLoopback() {
+		if err := createLoopbackFile(v.loopbackPath(), v.opts.Size); err != nil {
+			return err
+		}
+	}
+	if v.needsMount() {
+		return nil
+	}
+	return v.applyOwnership()
+}
+
+func (v *localVolume) loopbackPath() string {
+	return filepath.Join(filepath.Dir(v.path), loopbackFileName)
+}
+
 func (v *localVolume) mount() error {
+	if err := v.mountPath(); err != nil {
+		return err
+	}
+	if err := v.applyOwnership(); err != nil {
+		mount.Unmount(v.path)
+		return err
+	}
+	return nil
+}
+
+func (v *localVolume) mountPath() error {
+	if v.usesLoopback() {
+		return mountLoopbackFile(v.loopbackPath(), v.path)
+	}
 	if v.opts.MountDevice == "" {
 		return fmt.Errorf("missing device in volume options")
 	}
-	return mount.Mount(v.opts.MountDevice, v.path, v.opts.MountType, v.opts.MountOpts)
+	mountOpts := v.opts.MountOpts
+	if v.opts.MountType == "tmpfs" && v.opts.Size > 0 {
+		sizeOpt := fmt.Sprintf("size=%d", v.opts.Size)
+		if mountOpts == "" {
+			mountOpts = sizeOpt
+		} else {
+			mountOpts = mountOpts + "," + sizeOpt
+		}
+	}
+	return mount.Mount(v.opts.MountDevice, v.path, v.opts.MountType, mountOpts)
+}
+
+// applyOwnership sets the owner, group and permissions of the volume root
+// as requested through the uid, gid and mode options.
+func (v *localVolume) applyOwnership() error {
+	uid, gid := -1, -1
+	if v.opts.UID != nil {
+		uid = *v.opts.UID
+	}
+	if v.opts.GID != nil {
+		gid = *v.opts.GID
+	}
+	if uid != -1 || gid != -1 {
+		if err := os.Chown(v.path, uid, gid); err != nil {
+			return err
+		}
+	}
+	if v.opts.Mode != nil {
+		return os.Chmod(v.path, *v.opts.Mode)
+	}
+	return nil
 }
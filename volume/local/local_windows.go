@@ -32,3 +32,11 @@ func setOpts(v *localVolume, opts map[string]string) error {
 func (v *localVolume) mount() error {
 	return nil
 }
+
+func (v *localVolume) needsMount() bool {
+	return false
+}
+
+func (v *localVolume) setup() error {
+	return nil
+}
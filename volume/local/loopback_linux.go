@@ -0,0 +1,43 @@
+package local
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/docker/docker/pkg/loopback"
+	"github.com/docker/docker/pkg/mount"
+)
+
+// createLoopbackFile creates a sparse file of the given size at path and
+// formats it with an ext4 filesystem.
+func createLoopbackFile(path string, size int64) error {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return err
+	}
+	if err := f.Truncate(size); err != nil {
+		f.Close()
+		os.Remove(path)
+		return err
+	}
+	f.Close()
+
+	if out, err := exec.Command("mkfs.ext4", "-q", "-F", "-E", "nodiscard", path).CombinedOutput(); err != nil {
+		os.Remove(path)
+		return fmt.Errorf("error formatting volume backing file: %v: %s", err, out)
+	}
+	return nil
+}
+
+// mountLoopbackFile attaches the backing file at path to a loop device and
+// mounts it on target. The loop device is set to auto-clear, so it is
+// released once target is unmounted.
+func mountLoopbackFile(path, target string) error {
+	loopFile, err := loopback.AttachLoopDevice(path)
+	if err != nil {
+		return err
+	}
+	defer loopFile.Close()
+	return mount.Mount(loopFile.Name(), target, "ext4", "")
+}
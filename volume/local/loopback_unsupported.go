@@ -0,0 +1,13 @@
+// +build freebsd solaris
+
+package local
+
+import "fmt"
+
+func createLoopbackFile(path string, size int64) error {
+	return fmt.Errorf("size option is not supported on this platform")
+}
+
+func mountLoopbackFile(path, target string) error {
+	return fmt.Errorf("size option is not supported on this platform")
+}